@@ -0,0 +1,107 @@
+package scopes
+
+import "strings"
+
+// CompiledGiven is a preprocessed form of a Given set that answers
+// SatisfiesScope and Satisfies queries much faster than Given's own linear
+// scan does: literal scopes are kept in a hash set (O(1) lookup), and
+// star-prefixed scopes are inserted into a trie keyed by byte, so testing
+// whether any of them is a prefix of a scope costs O(len(scope)) -
+// independent of how many star scopes are registered - rather than a scan
+// of every one of them. It is intended for callers, such as an authorizing
+// proxy, that hold onto a large Given set and evaluate many Required
+// values against it.
+type CompiledGiven struct {
+	literals map[string]bool
+	prefixes *prefixNode
+}
+
+// prefixNode is a node in a trie over the characters of star-prefixed
+// given scopes (with the trailing "*" stripped). A node with end == true
+// means the path from the root to it spells out a registered prefix.
+type prefixNode struct {
+	children map[byte]*prefixNode
+	end      bool
+}
+
+func newPrefixNode() *prefixNode {
+	return &prefixNode{children: map[byte]*prefixNode{}}
+}
+
+func (n *prefixNode) insert(prefix string) {
+	cur := n
+	for i := 0; i < len(prefix); i++ {
+		b := prefix[i]
+		child, ok := cur.children[b]
+		if !ok {
+			child = newPrefixNode()
+			cur.children[b] = child
+		}
+		cur = child
+	}
+	cur.end = true
+}
+
+// hasPrefixOf reports whether any prefix stored in the trie rooted at `n`
+// is a prefix of `s`.
+func (n *prefixNode) hasPrefixOf(s string) bool {
+	if n.end {
+		// the empty string was registered, i.e. the given scope was "*"
+		return true
+	}
+	cur := n
+	for i := 0; i < len(s); i++ {
+		child, ok := cur.children[s[i]]
+		if !ok {
+			return false
+		}
+		if child.end {
+			return true
+		}
+		cur = child
+	}
+	return false
+}
+
+// Compile preprocesses `given` into a CompiledGiven. The result reflects
+// the scopes present in `given` at the time Compile is called; mutating
+// `given` afterwards has no effect on it.
+func (given Given) Compile() *CompiledGiven {
+	c := &CompiledGiven{
+		literals: map[string]bool{},
+		prefixes: newPrefixNode(),
+	}
+	for _, s := range given {
+		if strings.HasSuffix(s, "*") {
+			c.prefixes.insert(s[:len(s)-1])
+		} else {
+			c.literals[s] = true
+		}
+	}
+	return c
+}
+
+// SatisfiesScope reports whether the compiled given set satisfies the
+// single scope `s`.
+func (c *CompiledGiven) SatisfiesScope(s string) bool {
+	return c.literals[s] || c.prefixes.hasPrefixOf(s)
+}
+
+// Satisfies reports whether the compiled given set satisfies `required`,
+// short-circuiting as soon as one scope set of `required` is fully
+// satisfied, with the same semantics as Given.Satisfies.
+func (c *CompiledGiven) Satisfies(required *Required) bool {
+	for _, set := range *required {
+		satisfied := true
+		for _, scope := range set {
+			if !c.SatisfiesScope(scope) {
+				satisfied = false
+				break
+			}
+		}
+		if satisfied {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package scopes
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestCompiledGivenSatisfiesScope(t *testing.T) {
+	tests := []struct {
+		name  string
+		given Given
+		scope string
+		want  bool
+	}{
+		{"literal match", Given{"abc:def"}, "abc:def", true},
+		{"literal mismatch", Given{"abc:def"}, "abc:deg", false},
+		{"star match", Given{"abc:*"}, "abc:def", true},
+		{"star matches itself as prefix", Given{"abc:*"}, "abc:", true},
+		{"star does not match unrelated scope", Given{"abc:*"}, "xyz:def", false},
+		{"bare star matches everything", Given{"*"}, "anything:at:all", true},
+		{"both overlapping prefixes match", Given{"a*", "ac*"}, "ac:def", true},
+		{"only a shorter sibling prefix matches", Given{"a*", "ac*"}, "ad:def", true},
+		{"no prefix matches", Given{"a*", "ac*"}, "xyz", false},
+		{"empty given", Given{}, "abc:def", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := tt.given.Compile()
+			if got := c.SatisfiesScope(tt.scope); got != tt.want {
+				t.Errorf("SatisfiesScope(%q) with given %v = %v, want %v", tt.scope, tt.given, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompiledGivenSatisfies(t *testing.T) {
+	given := Given{"abc:*", "123:4:56", "xyz"}
+	required := Required{
+		{"abc:def", "AB:CD:EF"},
+		{"123:4:56"},
+		{"abc:def", "123:4:56"},
+		{"Xxyz"},
+	}
+	c := given.Compile()
+	if !c.Satisfies(&required) {
+		t.Errorf("expected %v to satisfy %v via the 123:4:56 scope set", given, required)
+	}
+
+	unsatisfiable := Required{{"abc:def", "AB:CD:EF"}, {"Xxyz"}}
+	if c.Satisfies(&unsatisfiable) {
+		t.Errorf("did not expect %v to satisfy %v", given, unsatisfiable)
+	}
+}
+
+// TestCompiledGivenAgreesWithGiven checks CompiledGiven against the
+// unindexed Given implementation across a given set of many star prefixes
+// of varying lengths, the shape that regressed the earlier,
+// length-bucketed version of CompiledGiven.
+func TestCompiledGivenAgreesWithGiven(t *testing.T) {
+	given := manyDistinctLengthPrefixes(500)
+	compiled := given.Compile()
+
+	scopes := []string{
+		"scope-0:create-task:abc",
+		"scope-250:create-task:abc",
+		"scope-499:create-task:abc",
+		"no-such-scope:create-task:abc",
+	}
+	for _, scope := range scopes {
+		want := given.satisfiesScope(&scope)
+		got := compiled.SatisfiesScope(scope)
+		if got != want {
+			t.Errorf("SatisfiesScope(%q) = %v, want %v (to match Given.satisfiesScope)", scope, got, want)
+		}
+	}
+}
+
+// manyDistinctLengthPrefixes returns n star-prefixed scopes whose prefixes
+// come in nine different lengths (each number is zero-padded to a width
+// that cycles from 1 to 9 digits), the pattern that defeated the
+// length-bucketed binary search in the original CompiledGiven.
+func manyDistinctLengthPrefixes(n int) Given {
+	given := make(Given, n)
+	for i := 0; i < n; i++ {
+		given[i] = fmt.Sprintf("scope-%0*d:*", i%9+1, i)
+	}
+	return given
+}
+
+func BenchmarkGivenSatisfiesScope(b *testing.B) {
+	given := manyDistinctLengthPrefixes(500)
+	scope := "scope-499:create-task:abc"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		given.satisfiesScope(&scope)
+	}
+}
+
+func BenchmarkCompiledGivenSatisfiesScope(b *testing.B) {
+	given := manyDistinctLengthPrefixes(500)
+	compiled := given.Compile()
+	scope := "scope-499:create-task:abc"
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		compiled.SatisfiesScope(scope)
+	}
+}
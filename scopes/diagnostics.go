@@ -0,0 +1,92 @@
+package scopes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// UnsatisfiedError is returned by Given.SatisfactionError when none of the
+// scope sets in a Required are satisfied. It records, for every scope set,
+// which of its scopes were missing and which given scope (literal or
+// `prefix*`) satisfied the ones that did match, so callers can log or
+// return an actionable message, for example in a 403 response body.
+type UnsatisfiedError struct {
+	Required Required
+	Sets     []UnsatisfiedSet
+}
+
+// UnsatisfiedSet describes how a single scope set within a Required fared
+// against a Given set.
+type UnsatisfiedSet struct {
+	// Missing lists the scopes in the set that given did not satisfy.
+	Missing []string
+	// SatisfiedBy maps each scope in the set that was satisfied to the
+	// given scope that satisfied it.
+	SatisfiedBy map[string]string
+}
+
+// SatisfactionError returns nil if `given` satisfies `required`, and
+// otherwise an *UnsatisfiedError describing exactly which scopes were
+// missing from each scope set of `required`.
+func (given *Given) SatisfactionError(required *Required) error {
+	if given.Satisfies(required) {
+		return nil
+	}
+	err := &UnsatisfiedError{Required: *required}
+	for _, set := range *required {
+		us := UnsatisfiedSet{SatisfiedBy: map[string]string{}}
+		for _, scope := range set {
+			if by, ok := given.satisfiedByScope(scope); ok {
+				us.SatisfiedBy[scope] = by
+			} else {
+				us.Missing = append(us.Missing, scope)
+			}
+		}
+		err.Sets = append(err.Sets, us)
+	}
+	return err
+}
+
+// satisfiedByScope returns the given scope that satisfies requiredScope, if
+// any.
+func (given *Given) satisfiedByScope(requiredScope string) (string, bool) {
+	for _, givenScope := range *given {
+		gs, rs := givenScope, requiredScope
+		if scopeMatch(&gs, &rs) {
+			return gs, true
+		}
+	}
+	return "", false
+}
+
+// Error renders the Required's DNF in the same "AND"/"OR" form used in the
+// package documentation, marking scopes that were missing with a leading
+// "-", for example:
+//
+//	("abc:def" AND -"AB:CD:EF") OR -"123:4:5"
+func (e *UnsatisfiedError) Error() string {
+	parts := make([]string, len(e.Sets))
+	for i, set := range e.Sets {
+		parts[i] = set.render(e.Required[i])
+	}
+	return strings.Join(parts, " OR ")
+}
+
+func (set UnsatisfiedSet) render(scopes scopeSet) string {
+	missing := map[string]bool{}
+	for _, m := range set.Missing {
+		missing[m] = true
+	}
+	atoms := make([]string, len(scopes))
+	for i, s := range scopes {
+		if missing[s] {
+			atoms[i] = fmt.Sprintf("-%q", s)
+		} else {
+			atoms[i] = fmt.Sprintf("%q", s)
+		}
+	}
+	if len(atoms) == 1 {
+		return atoms[0]
+	}
+	return "(" + strings.Join(atoms, " AND ") + ")"
+}
@@ -0,0 +1,60 @@
+package scopes
+
+import "testing"
+
+func TestGivenSatisfactionError(t *testing.T) {
+	given := Given{"abc:*", "123:4:56"}
+
+	required := Required{{"abc:def", "123:4:56"}}
+	if err := given.SatisfactionError(&required); err != nil {
+		t.Errorf("expected nil error when %v satisfies %v, got %v", given, required, err)
+	}
+
+	unsatisfiable := Required{
+		{"abc:def", "AB:CD:EF"},
+		{"Xxyz"},
+	}
+	err := given.SatisfactionError(&unsatisfiable)
+	if err == nil {
+		t.Fatalf("expected an error when %v does not satisfy %v", given, unsatisfiable)
+	}
+
+	uerr, ok := err.(*UnsatisfiedError)
+	if !ok {
+		t.Fatalf("expected a *UnsatisfiedError, got %T", err)
+	}
+	if len(uerr.Sets) != 2 {
+		t.Fatalf("expected 2 scope sets recorded, got %d", len(uerr.Sets))
+	}
+
+	first := uerr.Sets[0]
+	if by := first.SatisfiedBy["abc:def"]; by != "abc:*" {
+		t.Errorf("expected \"abc:def\" to be satisfied by \"abc:*\", got %q", by)
+	}
+	if len(first.Missing) != 1 || first.Missing[0] != "AB:CD:EF" {
+		t.Errorf("expected [\"AB:CD:EF\"] missing, got %v", first.Missing)
+	}
+
+	second := uerr.Sets[1]
+	if len(second.Missing) != 1 || second.Missing[0] != "Xxyz" {
+		t.Errorf("expected [\"Xxyz\"] missing, got %v", second.Missing)
+	}
+
+	wantMsg := `("abc:def" AND -"AB:CD:EF") OR -"Xxyz"`
+	if err.Error() != wantMsg {
+		t.Errorf("Error() = %q, want %q", err.Error(), wantMsg)
+	}
+}
+
+func TestGivenSatisfactionErrorSingleScopeSet(t *testing.T) {
+	given := Given{"abc:*"}
+	required := Required{{"xyz"}}
+
+	err := given.SatisfactionError(&required)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if want := `-"xyz"`; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,142 @@
+package scopes
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expression represents a (possibly nested) boolean requirement over
+// scopes, built from And, Or, Not and Scope. Unlike Required, which is
+// restricted to disjunctive normal form, an Expression may combine these
+// freely; call ToRequired to flatten one into the DNF shape that
+// Given.Satisfies expects.
+type Expression interface {
+	// Evaluate reports whether `given` satisfies this expression, along
+	// with an Explanation of how the result was reached.
+	Evaluate(given Given) (bool, Explanation)
+	// ToRequired flattens the expression into disjunctive normal form,
+	// distributing ANDs over ORs as necessary. It panics if the
+	// expression contains a Not, since negation cannot in general be
+	// expressed in DNF over literal scopes.
+	ToRequired() Required
+}
+
+// Explanation is a human-readable account of how an Expression evaluated
+// against a Given set, suitable for logging or inclusion in error messages.
+type Explanation string
+
+// Scope returns an Expression satisfied when `given` satisfies the single
+// literal required scope `scope`.
+func Scope(scope string) Expression {
+	return scopeExpr(scope)
+}
+
+type scopeExpr string
+
+func (e scopeExpr) Evaluate(given Given) (bool, Explanation) {
+	scope := string(e)
+	if given.satisfiesScope(&scope) {
+		return true, Explanation(fmt.Sprintf("%q", scope))
+	}
+	return false, Explanation(fmt.Sprintf("-%q", scope))
+}
+
+func (e scopeExpr) ToRequired() Required {
+	return Required{{string(e)}}
+}
+
+// And returns an Expression satisfied only when every one of `exprs` is
+// satisfied.
+func And(exprs ...Expression) Expression {
+	return andExpr(exprs)
+}
+
+type andExpr []Expression
+
+func (e andExpr) Evaluate(given Given) (bool, Explanation) {
+	satisfied := true
+	parts := make([]string, len(e))
+	for i, sub := range e {
+		ok, exp := sub.Evaluate(given)
+		if !ok {
+			satisfied = false
+		}
+		parts[i] = string(exp)
+	}
+	return satisfied, Explanation("(" + strings.Join(parts, " AND ") + ")")
+}
+
+func (e andExpr) ToRequired() Required {
+	// The identity for AND under this representation is a single empty
+	// scope set, which distributeAnd then grows with each sub-expression.
+	result := Required{{}}
+	for _, sub := range e {
+		result = distributeAnd(result, sub.ToRequired())
+	}
+	return result
+}
+
+// distributeAnd computes the cartesian product of two Required values,
+// merging each pair of scope sets together. This is how ANDs of ORs get
+// distributed out into disjunctive normal form.
+func distributeAnd(a, b Required) Required {
+	out := make(Required, 0, len(a)*len(b))
+	for _, setA := range a {
+		for _, setB := range b {
+			merged := make(scopeSet, 0, len(setA)+len(setB))
+			merged = append(merged, setA...)
+			merged = append(merged, setB...)
+			out = append(out, merged)
+		}
+	}
+	return out
+}
+
+// Or returns an Expression satisfied when any one of `exprs` is satisfied.
+func Or(exprs ...Expression) Expression {
+	return orExpr(exprs)
+}
+
+type orExpr []Expression
+
+func (e orExpr) Evaluate(given Given) (bool, Explanation) {
+	satisfied := false
+	parts := make([]string, len(e))
+	for i, sub := range e {
+		ok, exp := sub.Evaluate(given)
+		if ok {
+			satisfied = true
+		}
+		parts[i] = string(exp)
+	}
+	return satisfied, Explanation("(" + strings.Join(parts, " OR ") + ")")
+}
+
+func (e orExpr) ToRequired() Required {
+	out := make(Required, 0, len(e))
+	for _, sub := range e {
+		out = append(out, sub.ToRequired()...)
+	}
+	return out
+}
+
+// Not returns an Expression satisfied exactly when `expr` is not. Negation
+// is only meaningful against a concrete Given set, so Not expressions must
+// be evaluated directly with Evaluate; calling ToRequired on one (or on any
+// expression containing one) panics.
+func Not(expr Expression) Expression {
+	return notExpr{expr}
+}
+
+type notExpr struct {
+	expr Expression
+}
+
+func (e notExpr) Evaluate(given Given) (bool, Explanation) {
+	ok, exp := e.expr.Evaluate(given)
+	return !ok, Explanation("NOT " + string(exp))
+}
+
+func (e notExpr) ToRequired() Required {
+	panic("scopes: cannot flatten an Expression containing Not into disjunctive normal form")
+}
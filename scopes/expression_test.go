@@ -0,0 +1,82 @@
+package scopes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpressionEvaluate(t *testing.T) {
+	given := Given{"abc:*", "123:4:56"}
+	tests := []struct {
+		name string
+		expr Expression
+		want bool
+	}{
+		{"literal satisfied", Scope("abc:def"), true},
+		{"literal unsatisfied", Scope("xyz"), false},
+		{"and both satisfied", And(Scope("abc:def"), Scope("123:4:56")), true},
+		{"and one unsatisfied", And(Scope("abc:def"), Scope("xyz")), false},
+		{"or one satisfied", Or(Scope("xyz"), Scope("abc:def")), true},
+		{"or none satisfied", Or(Scope("xyz"), Scope("Xxyz")), false},
+		{"not of satisfied is false", Not(Scope("abc:def")), false},
+		{"not of unsatisfied is true", Not(Scope("xyz")), true},
+		{"nested", Or(And(Scope("abc:def"), Not(Scope("xyz"))), Scope("nope")), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, exp := tt.expr.Evaluate(given)
+			if got != tt.want {
+				t.Errorf("Evaluate() = %v (%s), want %v", got, exp, tt.want)
+			}
+			if exp == "" {
+				t.Errorf("Evaluate() returned an empty Explanation")
+			}
+		})
+	}
+}
+
+func TestExpressionToRequired(t *testing.T) {
+	tests := []struct {
+		name string
+		expr Expression
+		want Required
+	}{
+		{
+			"single scope",
+			Scope("abc:def"),
+			Required{{"abc:def"}},
+		},
+		{
+			"or of scopes",
+			Or(Scope("abc:def"), Scope("123:4:5")),
+			Required{{"abc:def"}, {"123:4:5"}},
+		},
+		{
+			"and of scopes",
+			And(Scope("abc:def"), Scope("AB:CD:EF")),
+			Required{{"abc:def", "AB:CD:EF"}},
+		},
+		{
+			"and distributes over or",
+			And(Scope("abc:def"), Or(Scope("123:4:5"), Scope("AB:CD:EF"))),
+			Required{{"abc:def", "123:4:5"}, {"abc:def", "AB:CD:EF"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.expr.ToRequired()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToRequired() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpressionToRequiredPanicsOnNot(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected ToRequired() to panic on an expression containing Not")
+		}
+	}()
+	And(Scope("abc:def"), Not(Scope("xyz"))).ToRequired()
+}
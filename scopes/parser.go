@@ -0,0 +1,150 @@
+package scopes
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseExpression parses a textual scope expression such as:
+//
+//	("abc:def" AND "AB:CD:EF") OR "123:4:5"
+//
+// into an Expression tree, so that services can carry required-scope
+// expressions as plain strings in configuration or JSON. Scopes must be
+// double-quoted Go string literals; "AND", "OR" and parentheses combine
+// them, with "AND" binding tighter than "OR". There is no textual form for
+// Not; build those with the Not function directly.
+func ParseExpression(s string) (Expression, error) {
+	p := &exprParser{tokens: tokenizeExpression(s)}
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("scopes: unexpected token %q", p.tokens[p.pos])
+	}
+	return expr, nil
+}
+
+// tokenizeExpression splits a scope expression into "(", ")", "AND", "OR"
+// and quoted-string tokens (the quotes are left intact, so later stages can
+// use strconv.Unquote).
+func tokenizeExpression(s string) []string {
+	var tokens []string
+	i := 0
+	for i < len(s) {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')':
+			tokens = append(tokens, string(c))
+			i++
+		case c == '"':
+			j := i + 1
+			for j < len(s) && s[j] != '"' {
+				if s[j] == '\\' && j+1 < len(s) {
+					j++
+				}
+				j++
+			}
+			if j < len(s) {
+				j++ // include closing quote
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			j := i
+			for j < len(s) && s[j] != ' ' && s[j] != '\t' && s[j] != '\n' && s[j] != '\r' && s[j] != '(' && s[j] != ')' {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+// parseOr := parseAnd ("OR" parseAnd)*
+func (p *exprParser) parseOr() (Expression, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expression{first}
+	for p.peek() == "OR" {
+		p.pos++
+		next, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, next)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return Or(exprs...), nil
+}
+
+// parseAnd := parsePrimary ("AND" parsePrimary)*
+func (p *exprParser) parseAnd() (Expression, error) {
+	first, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	exprs := []Expression{first}
+	for p.peek() == "AND" {
+		p.pos++
+		next, err := p.parsePrimary()
+		if err != nil {
+			return nil, err
+		}
+		exprs = append(exprs, next)
+	}
+	if len(exprs) == 1 {
+		return exprs[0], nil
+	}
+	return And(exprs...), nil
+}
+
+// parsePrimary := "(" parseOr ")" | quoted-scope
+func (p *exprParser) parsePrimary() (Expression, error) {
+	tok := p.peek()
+	switch {
+	case tok == "":
+		return nil, fmt.Errorf("scopes: unexpected end of expression")
+	case tok == "(":
+		p.pos++
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ")" {
+			return nil, fmt.Errorf("scopes: expected ')', got %q", p.peek())
+		}
+		p.pos++
+		return expr, nil
+	case strings.HasPrefix(tok, "\""):
+		p.pos++
+		scope, err := strconv.Unquote(tok)
+		if err != nil {
+			return nil, fmt.Errorf("scopes: invalid scope literal %q: %v", tok, err)
+		}
+		return Scope(scope), nil
+	default:
+		return nil, fmt.Errorf("scopes: unexpected token %q", tok)
+	}
+}
@@ -0,0 +1,75 @@
+package scopes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseExpression(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  Required
+	}{
+		{
+			"single scope",
+			`"abc:def"`,
+			Required{{"abc:def"}},
+		},
+		{
+			"and",
+			`"abc:def" AND "AB:CD:EF"`,
+			Required{{"abc:def", "AB:CD:EF"}},
+		},
+		{
+			"or",
+			`"abc:def" OR "123:4:5"`,
+			Required{{"abc:def"}, {"123:4:5"}},
+		},
+		{
+			"and binds tighter than or",
+			`"abc:def" AND "AB:CD:EF" OR "123:4:5"`,
+			Required{{"abc:def", "AB:CD:EF"}, {"123:4:5"}},
+		},
+		{
+			"parenthesized",
+			`("abc:def" AND "AB:CD:EF") OR "123:4:5"`,
+			Required{{"abc:def", "AB:CD:EF"}, {"123:4:5"}},
+		},
+		{
+			"nested parens",
+			`"123:4:5" OR ("abc:def" AND ("AB:CD:EF" OR "Xxyz"))`,
+			Required{{"123:4:5"}, {"abc:def", "AB:CD:EF"}, {"abc:def", "Xxyz"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr, err := ParseExpression(tt.input)
+			if err != nil {
+				t.Fatalf("ParseExpression(%q) returned error: %v", tt.input, err)
+			}
+			got := expr.ToRequired()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExpression(%q).ToRequired() = %#v, want %#v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpressionErrors(t *testing.T) {
+	tests := []string{
+		``,
+		`"abc:def" AND`,
+		`("abc:def"`,
+		`"abc:def")`,
+		`"abc:def" "AB:CD:EF"`,
+		`"unterminated`,
+	}
+	for _, input := range tests {
+		t.Run(input, func(t *testing.T) {
+			if _, err := ParseExpression(input); err == nil {
+				t.Errorf("ParseExpression(%q) expected an error, got none", input)
+			}
+		})
+	}
+}
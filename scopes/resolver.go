@@ -0,0 +1,185 @@
+package scopes
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// assumePrefix is the scope prefix a client uses to gain the scopes of a
+// role, mirroring how Taskcluster clients assume roles.
+const assumePrefix = "assume:"
+
+// A Resolver expands the scopes a client is given directly into the full
+// set of effective scopes, by adding to each `assume:<role>` (and
+// `assume:<prefix>*`) scope the scopes granted by matching roles; the
+// assume: scopes themselves are kept.
+// The zero value is not usable; create one with NewResolver.
+type Resolver struct {
+	mu    sync.RWMutex
+	roles map[string][]string
+}
+
+// NewResolver returns an empty Resolver with no roles registered.
+func NewResolver() *Resolver {
+	return &Resolver{roles: map[string][]string{}}
+}
+
+// AddRole registers a role named `name` (without the `assume:` prefix) that
+// grants `scopes` to any Given set that assumes it, either directly via
+// `assume:<name>` or via a `assume:<prefix>*` that matches it.
+func (r *Resolver) AddRole(name string, scopes []string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.roles[name] = append([]string(nil), scopes...)
+}
+
+// ExpandScopes repeatedly adds, for each `assume:<role>` scope in `given`,
+// the scopes granted by the matching role(s), keeping the assume: scope
+// itself, until a fixed point is
+// reached: once an iteration adds no new scope, expansion stops. This
+// makes expansion terminate even in the presence of roles that (directly
+// or transitively) assume each other, since a role's scopes are only ever
+// added once.
+func (r *Resolver) ExpandScopes(given Given) Given {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	seen := map[string]bool{}
+	queue := make([]string, 0, len(given))
+	for _, s := range given {
+		if !seen[s] {
+			seen[s] = true
+			queue = append(queue, s)
+		}
+	}
+
+	for i := 0; i < len(queue); i++ {
+		scope := queue[i]
+		if !strings.HasPrefix(scope, assumePrefix) {
+			continue
+		}
+		roleRef := scope[len(assumePrefix):]
+		for name, roleScopes := range r.roles {
+			if !roleMatches(roleRef, name) {
+				continue
+			}
+			for _, rs := range roleScopes {
+				if !seen[rs] {
+					seen[rs] = true
+					queue = append(queue, rs)
+				}
+			}
+		}
+	}
+
+	return NormalizeGiven(Given(queue))
+}
+
+// roleMatches reports whether the role reference taken from an
+// `assume:<roleRef>` scope (either a literal role name, or a
+// `<prefix>*` star) matches the registered role `name`.
+func roleMatches(roleRef, name string) bool {
+	if roleRef == name {
+		return true
+	}
+	return strings.HasSuffix(roleRef, "*") && strings.HasPrefix(name, roleRef[:len(roleRef)-1])
+}
+
+// DetectCycles returns the names of any registered roles that are
+// reachable from themselves by repeatedly following assume:<role> scopes,
+// for example role "a" granting "assume:b" while "b" grants "assume:a".
+// ExpandScopes tolerates such cycles without needing this check first
+// (each role's scopes are only ever added once, so expansion still
+// terminates), but it does not itself report them; call DetectCycles
+// separately, for example when roles are loaded, to catch a
+// misconfiguration early.
+func (r *Resolver) DetectCycles() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var cyclic []string
+	for name := range r.roles {
+		if r.reaches(name, name, map[string]bool{}) {
+			cyclic = append(cyclic, name)
+		}
+	}
+	sort.Strings(cyclic)
+	return cyclic
+}
+
+// reaches reports whether, starting from the assume:* scopes of `current`,
+// it is possible to reach the role `target` again. `visited` guards
+// against re-exploring a role within the same search, so this terminates
+// even when `current` sits in a cycle that does not itself include
+// `target`.
+func (r *Resolver) reaches(target, current string, visited map[string]bool) bool {
+	if visited[current] {
+		return false
+	}
+	visited[current] = true
+	for _, scope := range r.roles[current] {
+		if !strings.HasPrefix(scope, assumePrefix) {
+			continue
+		}
+		roleRef := scope[len(assumePrefix):]
+		for name := range r.roles {
+			if !roleMatches(roleRef, name) {
+				continue
+			}
+			if name == target || r.reaches(target, name, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// NormalizeGiven returns a sorted copy of `given` with any scope already
+// covered by a broader "<prefix>*" sibling removed, so that two Given sets
+// granting the same effective permissions compare equal and can be used as
+// cache keys.
+func NormalizeGiven(given Given) Given {
+	all := append([]string(nil), given...)
+	sort.Strings(all)
+
+	var stars []string
+	for _, s := range all {
+		if strings.HasSuffix(s, "*") {
+			stars = append(stars, s)
+		}
+	}
+
+	out := make(Given, 0, len(all))
+	added := map[string]bool{}
+	for _, s := range all {
+		if added[s] {
+			continue
+		}
+		shadowed := false
+		for _, star := range stars {
+			if star == s {
+				continue
+			}
+			if strings.HasPrefix(s, star[:len(star)-1]) {
+				shadowed = true
+				break
+			}
+		}
+		if !shadowed {
+			added[s] = true
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// SatisfiesWith behaves like Satisfies, but first expands `given` through
+// `resolver` (if non-nil) before checking `required` against the result.
+func (given *Given) SatisfiesWith(required *Required, resolver *Resolver) bool {
+	if resolver == nil {
+		return given.Satisfies(required)
+	}
+	expanded := resolver.ExpandScopes(*given)
+	return expanded.Satisfies(required)
+}
@@ -0,0 +1,77 @@
+package scopes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResolverExpandScopes(t *testing.T) {
+	r := NewResolver()
+	r.AddRole("worker", []string{"queue:claim-task", "assume:base"})
+	r.AddRole("base", []string{"auth:current-scopes"})
+
+	got := r.ExpandScopes(Given{"assume:worker", "other:scope"})
+	want := Given{"assume:base", "assume:worker", "auth:current-scopes", "other:scope", "queue:claim-task"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandScopes() = %v, want %v", got, want)
+	}
+}
+
+func TestResolverExpandScopesStarRole(t *testing.T) {
+	r := NewResolver()
+	r.AddRole("repo:myorg/myrepo:*", []string{"queue:create-task:highest"})
+
+	got := r.ExpandScopes(Given{"assume:repo:*"})
+	want := Given{"assume:repo:*", "queue:create-task:highest"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandScopes() = %v, want %v", got, want)
+	}
+}
+
+func TestResolverExpandScopesTerminatesOnCycle(t *testing.T) {
+	r := NewResolver()
+	r.AddRole("a", []string{"scope:a", "assume:b"})
+	r.AddRole("b", []string{"scope:b", "assume:a"})
+
+	got := r.ExpandScopes(Given{"assume:a"})
+	want := Given{"assume:a", "assume:b", "scope:a", "scope:b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExpandScopes() = %v, want %v", got, want)
+	}
+}
+
+func TestResolverDetectCycles(t *testing.T) {
+	r := NewResolver()
+	r.AddRole("a", []string{"assume:b"})
+	r.AddRole("b", []string{"assume:a"})
+	r.AddRole("c", []string{"scope:c"})
+
+	got := r.DetectCycles()
+	want := []string{"a", "b"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DetectCycles() = %v, want %v", got, want)
+	}
+}
+
+func TestNormalizeGiven(t *testing.T) {
+	got := NormalizeGiven(Given{"abc:def", "abc:*", "xyz", "abc:def", "abc:*"})
+	want := Given{"abc:*", "xyz"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("NormalizeGiven() = %v, want %v", got, want)
+	}
+}
+
+func TestGivenSatisfiesWith(t *testing.T) {
+	r := NewResolver()
+	r.AddRole("worker", []string{"queue:claim-task"})
+
+	given := Given{"assume:worker"}
+	required := Required{{"queue:claim-task"}}
+
+	if given.SatisfiesWith(&required, r) != true {
+		t.Errorf("expected %v to satisfy %v via the worker role", given, required)
+	}
+	if given.SatisfiesWith(&required, nil) != false {
+		t.Errorf("expected %v to not satisfy %v without a resolver", given, required)
+	}
+}
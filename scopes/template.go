@@ -0,0 +1,125 @@
+package scopes
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// A Template is shaped exactly like a Required, except that its atoms may
+// contain "<name>" placeholders to be filled in later, for example
+// "queue:create-task:<workerType>". Substitute resolves the placeholders
+// against concrete parameters to produce an ordinary Required.
+type Template []templateSet
+
+type templateSet []string
+
+var placeholderPattern = regexp.MustCompile(`<([^<>]+)>`)
+
+// Substitute returns the Required obtained by replacing every "<name>"
+// placeholder in `t` with the URL-escaped value of params["name"], or an
+// error if any placeholder has no corresponding entry in `params`.
+func (t Template) Substitute(params map[string]string) (*Required, error) {
+	required := make(Required, len(t))
+	for i, set := range t {
+		resolvedSet := make(scopeSet, len(set))
+		for j, atom := range set {
+			resolved, err := substitutePlaceholders(atom, params)
+			if err != nil {
+				return nil, err
+			}
+			resolvedSet[j] = resolved
+		}
+		required[i] = resolvedSet
+	}
+	return &required, nil
+}
+
+func substitutePlaceholders(atom string, params map[string]string) (string, error) {
+	var missing []string
+	resolved := placeholderPattern.ReplaceAllStringFunc(atom, func(match string) string {
+		name := match[1 : len(match)-1]
+		value, ok := params[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return url.QueryEscape(value)
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("scopes: unresolved placeholder(s) %s in %q", strings.Join(missing, ", "), atom)
+	}
+	return resolved, nil
+}
+
+// Matcher is a function with the same signature and semantics as the
+// package's default matching rule, scopeMatch: it reports whether
+// givenScope satisfies requiredScope. Given.SatisfiesMatching accepts a
+// Matcher in place of the default rule, so callers can opt into extensions
+// such as ParamMatcher without changing the behaviour of Satisfies.
+type Matcher func(givenScope, requiredScope *string) bool
+
+// ParamMatcher extends the default matcher so that a "<param>" segment in a
+// given scope acts as a bounded wildcard, matching exactly one
+// colon-delimited segment of the required scope. This lets operators grant,
+// for example, "queue:create-task:<workerType>" without granting the
+// unbounded "queue:create-task:*".
+func ParamMatcher(givenScope, requiredScope *string) bool {
+	if scopeMatch(givenScope, requiredScope) {
+		return true
+	}
+	if !strings.Contains(*givenScope, "<") {
+		return false
+	}
+	givenParts := strings.Split(*givenScope, ":")
+	requiredParts := strings.Split(*requiredScope, ":")
+	if len(givenParts) != len(requiredParts) {
+		return false
+	}
+	for i, part := range givenParts {
+		if part == requiredParts[i] {
+			continue
+		}
+		if isParamSegment(part) && requiredParts[i] != "" {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
+func isParamSegment(segment string) bool {
+	return strings.HasPrefix(segment, "<") && strings.HasSuffix(segment, ">") && len(segment) > 2
+}
+
+// SatisfiesMatching behaves like Satisfies, but tests each given/required
+// scope pair with `matcher` instead of the default star-suffix rule.
+func (given *Given) SatisfiesMatching(required *Required, matcher Matcher) bool {
+	for _, set := range *required {
+		if given.setSatisfiedMatching(&set, matcher) {
+			return true
+		}
+	}
+	return false
+}
+
+func (given *Given) setSatisfiedMatching(set *scopeSet, matcher Matcher) bool {
+	for _, scope := range *set {
+		scope := scope
+		if !given.scopeSatisfiedMatching(&scope, matcher) {
+			return false
+		}
+	}
+	return true
+}
+
+func (given *Given) scopeSatisfiedMatching(requiredScope *string, matcher Matcher) bool {
+	for _, givenScope := range *given {
+		givenScope := givenScope
+		if matcher(&givenScope, requiredScope) {
+			return true
+		}
+	}
+	return false
+}
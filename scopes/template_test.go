@@ -0,0 +1,62 @@
+package scopes
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTemplateSubstitute(t *testing.T) {
+	tmpl := Template{{"queue:create-task:<workerType>", "queue:route:<route>"}}
+
+	got, err := tmpl.Substitute(map[string]string{"workerType": "my-worker", "route": "notify/email"})
+	if err != nil {
+		t.Fatalf("Substitute() returned error: %v", err)
+	}
+	want := &Required{{"queue:create-task:my-worker", "queue:route:notify%2Femail"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Substitute() = %#v, want %#v", got, want)
+	}
+}
+
+func TestTemplateSubstituteMissingParam(t *testing.T) {
+	tmpl := Template{{"queue:create-task:<workerType>"}}
+
+	if _, err := tmpl.Substitute(map[string]string{}); err == nil {
+		t.Error("expected an error for an unresolved placeholder, got none")
+	}
+}
+
+func TestParamMatcher(t *testing.T) {
+	tests := []struct {
+		name          string
+		givenScope    string
+		requiredScope string
+		want          bool
+	}{
+		{"literal still matches", "queue:create-task:abc", "queue:create-task:abc", true},
+		{"star still matches", "queue:*", "queue:create-task:abc", true},
+		{"param matches single segment", "queue:create-task:<workerType>", "queue:create-task:my-worker", true},
+		{"param does not match multiple segments", "queue:create-task:<workerType>", "queue:create-task:my:worker", false},
+		{"param does not match empty segment", "queue:create-task:<workerType>", "queue:create-task:", false},
+		{"param does not match different segment count", "queue:create-task:<workerType>", "queue:create-task", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParamMatcher(&tt.givenScope, &tt.requiredScope); got != tt.want {
+				t.Errorf("ParamMatcher(%q, %q) = %v, want %v", tt.givenScope, tt.requiredScope, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGivenSatisfiesMatchingWithParamMatcher(t *testing.T) {
+	given := Given{"queue:create-task:<workerType>"}
+	required := Required{{"queue:create-task:my-worker"}}
+
+	if !given.SatisfiesMatching(&required, ParamMatcher) {
+		t.Errorf("expected %v to satisfy %v via ParamMatcher", given, required)
+	}
+	if given.Satisfies(&required) {
+		t.Errorf("did not expect the default matcher to satisfy %v with %v", required, given)
+	}
+}